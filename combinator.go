@@ -1,11 +1,5 @@
 package waffleiron
 
-import (
-	"github.com/pkg/errors"
-
-	"github.com/hashicorp/go-multierror"
-)
-
 func And[T, U any](p0 Parser[T], p1 Parser[U]) Parser[Tuple2[T, U]] {
 	return andParser[T, U]{p0, p1}
 }
@@ -15,6 +9,10 @@ type andParser[T, U any] struct {
 	p1 Parser[U]
 }
 
+func (p andParser[T, U]) describe(w *describeWalker) Expr {
+	return Expr{Kind: KindSeq, Seq: []Expr{w.walk(p.p0), w.walk(p.p1)}}
+}
+
 func (p andParser[T, U]) Parse(r *Reader) (Tuple2[T, U], error) {
 	a, err := p.p0.Parse(r)
 	if err != nil {
@@ -37,6 +35,10 @@ type and3Parser[T, U, V any] struct {
 	p2 Parser[V]
 }
 
+func (p and3Parser[T, U, V]) describe(w *describeWalker) Expr {
+	return Expr{Kind: KindSeq, Seq: []Expr{w.walk(p.p0), w.walk(p.p1), w.walk(p.p2)}}
+}
+
 func (p and3Parser[T, U, V]) Parse(r *Reader) (Tuple3[T, U, V], error) {
 	v0, err := p.p0.Parse(r)
 	if err != nil {
@@ -66,8 +68,15 @@ type orParser[T any] struct {
 	ps []Parser[T]
 }
 
+func (p orParser[T]) describe(w *describeWalker) Expr {
+	alt := make([]Expr, len(p.ps))
+	for i, sub := range p.ps {
+		alt[i] = w.walk(sub)
+	}
+	return Expr{Kind: KindAlt, Alt: alt}
+}
+
 func (p orParser[T]) Parse(r *Reader) (T, error) {
-	var totalErr error
 	for _, p := range p.ps {
 		var t T
 		err := r.Try(func() error {
@@ -78,10 +87,99 @@ func (p orParser[T]) Parse(r *Reader) (T, error) {
 		if err == nil {
 			return t, nil
 		}
+	}
+	// Every branch failed; r.farthestError reports the branch that got
+	// furthest before failing, with the expected-sets of every branch
+	// that failed at that same position merged together.
+	return *new(T), r.farthestError()
+}
+
+// Try returns a Parser that behaves like p, but always undoes p's
+// partial consumption on failure. And's sub-parsers commit what they
+// consume immediately, so a failure partway through an And leaves the
+// reader advanced; wrapping a sub-parser in Try establishes a savepoint
+// instead, so a later Or (or another Try) can still backtrack past it.
+// In ParseStream, the savepoint also pins the buffered input so it
+// isn't released out from under a pending rewind.
+func Try[T any](p Parser[T]) Parser[T] {
+	return tryParser[T]{p}
+}
+
+type tryParser[T any] struct {
+	p Parser[T]
+}
+
+// describe inlines p.p: Try changes backtracking behavior, not the
+// shape of what's matched, so it has no effect on the grammar.
+func (p tryParser[T]) describe(w *describeWalker) Expr {
+	return w.walk(p.p)
+}
+
+func (p tryParser[T]) Parse(r *Reader) (T, error) {
+	var t T
+	err := r.Try(func() error {
+		var e error
+		t, e = p.p.Parse(r)
+		return e
+	})
+	return t, err
+}
+
+// Many returns a Parser that applies p zero or more times and collects
+// the results, stopping (without error) at the first position p fails
+// to match.
+func Many[T any](p Parser[T]) Parser[[]T] {
+	return manyParser[T]{p}
+}
+
+type manyParser[T any] struct {
+	p Parser[T]
+}
+
+func (p manyParser[T]) describe(w *describeWalker) Expr {
+	body := w.walk(p.p)
+	return Expr{Kind: KindRepeat, Repeat: &body, Min: 0}
+}
+
+// Parse implements Parser interface
+func (p manyParser[T]) Parse(r *Reader) ([]T, error) {
+	var out []T
+	for {
+		var v T
+		err := r.Try(func() error {
+			var e error
+			v, e = p.p.Parse(r)
+			return e
+		})
+		if err != nil {
+			return out, nil
+		}
+		out = append(out, v)
+	}
+}
+
+// Many1 is like Many, but requires at least one match.
+func Many1[T any](p Parser[T]) Parser[[]T] {
+	return many1Parser[T]{p}
+}
 
-		totalErr = multierror.Append(totalErr, err)
+type many1Parser[T any] struct {
+	p Parser[T]
+}
+
+func (p many1Parser[T]) describe(w *describeWalker) Expr {
+	body := w.walk(p.p)
+	return Expr{Kind: KindRepeat, Repeat: &body, Min: 1}
+}
+
+// Parse implements Parser interface
+func (p many1Parser[T]) Parse(r *Reader) ([]T, error) {
+	first, err := p.p.Parse(r)
+	if err != nil {
+		return nil, err
 	}
-	return *new(T), totalErr
+	rest, _ := Many(p.p).Parse(r)
+	return append([]T{first}, rest...), nil
 }
 
 func Trace[T any](name string, p Parser[T]) Parser[T] {
@@ -93,14 +191,78 @@ type traceParser[T any] struct {
 	p    Parser[T]
 }
 
+// describe registers p.name as a production rule the first time it is
+// reached; later references -- including a recursive one found while
+// still walking p's own body -- resolve to a reference instead of
+// being expanded again. See describeNamed.
+func (p traceParser[T]) describe(w *describeWalker) Expr {
+	return describeNamed(w, p.name, p.p)
+}
+
 func (p traceParser[T]) Parse(r *Reader) (T, error) {
+	startIdx, startPos := r.idx, r.pos
+
+	// labels already recorded at startIdx before this call, e.g. by an
+	// earlier Or branch failing at the same position; these survive
+	// below even though p.p's own labels at startIdx do not.
+	var priorLabels map[string]bool
+	if r.hasFarthest && r.farthestIdx == startIdx {
+		priorLabels = map[string]bool{}
+		for label := range r.farthestExpected {
+			priorLabels[label] = true
+		}
+	}
+
 	var t T
 	var err error
 	r.WithTrace(p.name, func() {
 		t, err = p.p.Parse(r)
 	})
-	if err != nil {
-		return t, errors.Wrapf(err, "at %q", p.name)
+	if err != nil && r.hasFarthest && r.farthestIdx == startIdx {
+		// p.p failed without making any progress: "expected <name>" is a
+		// more useful label for the user than whatever low-level token p
+		// bottomed out on, so it replaces p.p's own labels at startIdx.
+		expected := map[string]bool{p.name: true}
+		for label := range priorLabels {
+			expected[label] = true
+		}
+		r.farthestPos = startPos
+		r.farthestExpected = expected
+		return t, r.farthestError()
 	}
-	return t, nil
+	return t, err
+}
+
+// Lazy defers calling factory until the parser actually runs, which
+// lets a grammar refer to a rule before it's fully built, e.g. a
+// recursive rule referring to itself:
+//
+//	var expr Parser[int]
+//	expr = Trace("expr", Or(
+//		Map(And3(Lazy(func() Parser[int] { return expr }), Rune('+'), term), ...),
+//		term,
+//	))
+//
+// Without Lazy, the closure over expr above would capture expr's
+// zero value instead of the variable, since expr isn't assigned yet
+// at the point And3 is built.
+func Lazy[T any](factory func() Parser[T]) Parser[T] {
+	return lazyParser[T]{factory}
+}
+
+type lazyParser[T any] struct {
+	factory func() Parser[T]
+}
+
+// Parse implements Parser interface
+func (p lazyParser[T]) Parse(r *Reader) (T, error) {
+	return p.factory().Parse(r)
+}
+
+// describe walks whatever factory currently produces. Cycles are
+// still caught, because a recursive reference to a named (Trace/Memo)
+// rule will hit describeNamed's w.seen check just as it would without
+// Lazy in between.
+func (p lazyParser[T]) describe(w *describeWalker) Expr {
+	return w.walk(p.factory())
 }
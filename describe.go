@@ -0,0 +1,187 @@
+package waffleiron
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ExprKind identifies which field of Expr is populated.
+type ExprKind int
+
+const (
+	// KindRef is a reference to a named rule defined elsewhere in the
+	// Grammar (a Trace or Memo node already visited during the walk).
+	KindRef ExprKind = iota
+	// KindLiteral is text matched verbatim or case-insensitively.
+	KindLiteral
+	// KindPattern is a regexp or rune-class description.
+	KindPattern
+	// KindSeq is a sequence of sub-expressions (And/And3).
+	KindSeq
+	// KindAlt is a choice between sub-expressions (Or).
+	KindAlt
+	// KindRepeat is a repeated sub-expression (Many/Many1).
+	KindRepeat
+	// KindEmpty matches without consuming input (Pure).
+	KindEmpty
+)
+
+var exprKindNames = [...]string{"ref", "literal", "pattern", "seq", "alt", "repeat", "empty"}
+
+func (k ExprKind) String() string {
+	if int(k) < 0 || int(k) >= len(exprKindNames) {
+		return fmt.Sprintf("ExprKind(%d)", int(k))
+	}
+	return exprKindNames[k]
+}
+
+// MarshalJSON implements json.Marshaler
+func (k ExprKind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// Expr is one EBNF-like node in a Grammar produced by Describe. Which
+// of Ref, Literal, Pattern, Seq, Alt and Repeat is meaningful depends
+// on Kind.
+type Expr struct {
+	Kind    ExprKind
+	Ref     string `json:",omitempty"`
+	Literal string `json:",omitempty"`
+	Pattern string `json:",omitempty"`
+	Seq     []Expr `json:",omitempty"`
+	Alt     []Expr `json:",omitempty"`
+	Repeat  *Expr  `json:",omitempty"`
+	Min     int    `json:",omitempty"`
+}
+
+// Rule is one named production: the body of a Trace or Memo node.
+type Rule struct {
+	Name string
+	Body Expr
+}
+
+// Grammar is a self-contained description of a parser tree, built by
+// Describe. Root is the top-level expression; Rules holds the body of
+// every named (Trace or Memo) rule reachable from it, in the order
+// they were first encountered, so a grammar can be documented or
+// diffed across versions without hand-maintained docs.
+type Grammar struct {
+	Root  Expr
+	Rules []Rule
+}
+
+// describable is implemented by every node in a parser tree that
+// Describe knows how to walk. It is unexported and has no type
+// parameter so that generic combinators (andParser[T, U], orParser[T],
+// ...) can all implement it regardless of what they parse into.
+type describable interface {
+	describe(w *describeWalker) Expr
+}
+
+type describeWalker struct {
+	seen  map[string]bool
+	rules []Rule
+}
+
+// walk describes x, which must be some Parser[T]. x is typed as `any`
+// rather than Parser[any] because Go generics give no way to hold
+// Parser[T] values of differing T in one slice or interface otherwise;
+// describable sidesteps that by not mentioning T at all.
+func (w *describeWalker) walk(x any) Expr {
+	d, ok := x.(describable)
+	if !ok {
+		return Expr{Kind: KindPattern, Pattern: fmt.Sprintf("<opaque %T>", x)}
+	}
+	return d.describe(w)
+}
+
+// describeNamed implements the shared behavior of Trace and Memo: the
+// first time name is seen, its body is walked and recorded as a Rule;
+// any later reference -- including one found while still walking the
+// body, i.e. a recursive or left-recursive rule -- resolves to a plain
+// KindRef instead of recursing again.
+func describeNamed[T any](w *describeWalker, name string, p Parser[T]) Expr {
+	if w.seen[name] {
+		return Expr{Kind: KindRef, Ref: name}
+	}
+	w.seen[name] = true
+	i := len(w.rules)
+	w.rules = append(w.rules, Rule{Name: name})
+	body := w.walk(p)
+	w.rules[i].Body = body
+	return Expr{Kind: KindRef, Ref: name}
+}
+
+// Describe walks p's parser tree and returns an EBNF-like description
+// of the grammar it implements. Named Trace/Memo nodes become
+// production rules in the result; anonymous nodes are inlined where
+// they occur. Cycles -- recursive rules built with Lazy, or the
+// seed-growing left recursion in Memo -- are detected by name and
+// rendered as a reference rather than walked again.
+func Describe[T any](p Parser[T]) Grammar {
+	w := &describeWalker{seen: map[string]bool{}}
+	root := w.walk(p)
+	return Grammar{Root: root, Rules: w.rules}
+}
+
+// EBNF renders g as plain-text EBNF, with one production per rule, in
+// the order they were first reached from g.Root.
+func (g Grammar) EBNF() string {
+	var b strings.Builder
+	if g.Root.Kind != KindRef || !g.hasRule(g.Root.Ref) {
+		fmt.Fprintf(&b, "root = %s ;\n", renderExpr(g.Root))
+	}
+	for _, rule := range g.Rules {
+		fmt.Fprintf(&b, "%s = %s ;\n", rule.Name, renderExpr(rule.Body))
+	}
+	return b.String()
+}
+
+func (g Grammar) hasRule(name string) bool {
+	for _, rule := range g.Rules {
+		if rule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// JSON renders g as indented JSON, suitable for feeding into an
+// external railroad-diagram generator.
+func (g Grammar) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+func renderExpr(e Expr) string {
+	switch e.Kind {
+	case KindRef:
+		return e.Ref
+	case KindLiteral:
+		return fmt.Sprintf("%q", e.Literal)
+	case KindPattern:
+		return "? " + e.Pattern + " ?"
+	case KindEmpty:
+		return "ε"
+	case KindSeq:
+		return joinExpr(e.Seq, ", ")
+	case KindAlt:
+		return joinExpr(e.Alt, " | ")
+	case KindRepeat:
+		inner := renderExpr(*e.Repeat)
+		if e.Min == 0 {
+			return "{ " + inner + " }"
+		}
+		return inner + ", { " + inner + " }"
+	default:
+		return "?"
+	}
+}
+
+func joinExpr(es []Expr, sep string) string {
+	parts := make([]string, len(es))
+	for i, e := range es {
+		parts[i] = renderExpr(e)
+	}
+	return strings.Join(parts, sep)
+}
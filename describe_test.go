@@ -0,0 +1,72 @@
+package waffleiron_test
+
+import (
+	"strings"
+	"testing"
+
+	wi "github.com/seiyab/waffleiron"
+)
+
+func TestDescribeAnonymousCombinators(t *testing.T) {
+	p := wi.Many(wi.Or(wi.String("a"), wi.String("bc")))
+
+	g := wi.Describe(p)
+
+	if len(g.Rules) != 0 {
+		t.Fatalf("expected no named rules, got %d", len(g.Rules))
+	}
+	got := g.EBNF()
+	want := "root = { \"a\" | \"bc\" } ;\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDescribeTraceNamesARule(t *testing.T) {
+	ident := wi.Trace("ident", wi.Many1(wi.RuneWhere(func(r rune) bool { return r != ' ' })))
+
+	g := wi.Describe(ident)
+
+	if len(g.Rules) != 1 || g.Rules[0].Name != "ident" {
+		t.Fatalf("expected a single rule named ident, got %+v", g.Rules)
+	}
+	if g.Root.Kind != wi.KindRef || g.Root.Ref != "ident" {
+		t.Errorf("expected root to reference ident, got %+v", g.Root)
+	}
+}
+
+func TestDescribeMemoLeftRecursionStopsAtSelfReference(t *testing.T) {
+	var expr wi.Parser[int]
+	term := wi.Map(wi.RegexpStr(`[0-9]+`), func(string) int { return 0 })
+	expr = wi.Memo("expr", wi.Or(
+		wi.Map(wi.And3(wi.Lazy(func() wi.Parser[int] { return expr }), wi.Rune('+'), term), func(t wi.Tuple3[int, rune, int]) int { return 0 }),
+		term,
+	))
+
+	g := wi.Describe(expr)
+
+	if len(g.Rules) != 1 {
+		t.Fatalf("expected exactly one rule (no infinite recursion), got %d", len(g.Rules))
+	}
+	rule := g.Rules[0]
+	if rule.Name != "expr" {
+		t.Fatalf("expected rule named expr, got %q", rule.Name)
+	}
+	seq := rule.Body.Alt[0]
+	if seq.Seq[0].Kind != wi.KindRef || seq.Seq[0].Ref != "expr" {
+		t.Errorf("expected self-reference in left-recursive branch, got %+v", seq.Seq[0])
+	}
+}
+
+func TestGrammarEBNFListsRulesInDiscoveryOrder(t *testing.T) {
+	digit := wi.Trace("digit", wi.RegexpStr(`[0-9]`))
+	letter := wi.Trace("letter", wi.RegexpStr(`[a-z]`))
+	p := wi.And(letter, digit)
+
+	g := wi.Describe(p)
+
+	got := g.EBNF()
+	if i, j := strings.Index(got, "letter ="), strings.Index(got, "digit ="); i < 0 || j < 0 || i > j {
+		t.Errorf("expected letter before digit in %q", got)
+	}
+}
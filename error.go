@@ -0,0 +1,75 @@
+package waffleiron
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseError is the error returned when parsing fails. Rather than the
+// error of whichever branch happened to run last, it reports the
+// farthest position any parser reached before giving up, together with
+// the set of labels (drawn from Rune, String, Regexp and Trace) that
+// were expected there. This is the classic Parsec "longest match
+// failure" rule: of all the ways a grammar can fail on a given input,
+// the one that got furthest is almost always the one the user meant.
+type ParseError struct {
+	Pos      Pos
+	Got      rune
+	AtEOF    bool
+	Expected []string
+}
+
+// Error implements error
+func (e *ParseError) Error() string {
+	got := fmt.Sprintf("%q", e.Got)
+	if e.AtEOF {
+		got = "EOF"
+	}
+	return fmt.Sprintf("at %s: expected one of {%s}, got %s", e.Pos, strings.Join(e.Expected, ", "), got)
+}
+
+// recordFailure registers that a parser expected label at idx (with pos
+// as idx's line:col). If idx is farther than any failure recorded so
+// far, it becomes the new farthest failure and earlier labels are
+// discarded; if idx ties the current farthest failure, label is added
+// to the expected set alongside it.
+func (r *reader) recordFailure(idx int64, pos Pos, label string) {
+	if !r.hasFarthest || idx > r.farthestIdx {
+		r.hasFarthest = true
+		r.farthestIdx = idx
+		r.farthestPos = pos
+		r.farthestExpected = map[string]bool{}
+	}
+	if idx == r.farthestIdx {
+		r.farthestExpected[label] = true
+	}
+}
+
+// farthestError builds a ParseError from the farthest failure recorded
+// so far.
+func (r *reader) farthestError() error {
+	if !r.hasFarthest {
+		return fmt.Errorf("parse failed")
+	}
+	expected := make([]string, 0, len(r.farthestExpected))
+	for label := range r.farthestExpected {
+		expected = append(expected, label)
+	}
+	sort.Strings(expected)
+
+	pe := &ParseError{Pos: r.farthestPos, Expected: expected}
+	if r.src != nil {
+		if r.eof && r.farthestIdx >= r.base+int64(len(r.buf)) {
+			pe.AtEOF = true
+		} else {
+			pe.Got, _ = utf8.DecodeRune(r.buf[r.farthestIdx-r.base:])
+		}
+	} else if r.farthestIdx >= int64(len(r.str)) {
+		pe.AtEOF = true
+	} else {
+		pe.Got, _ = utf8.DecodeRuneInString(r.str[r.farthestIdx:])
+	}
+	return pe
+}
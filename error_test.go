@@ -0,0 +1,65 @@
+package waffleiron_test
+
+import (
+	"regexp"
+	"testing"
+
+	wi "github.com/seiyab/waffleiron"
+)
+
+func TestFarthestFailure(t *testing.T) {
+	digit := wi.Trace("digit", wi.RegexpStr("[0-9]+"))
+	letters := wi.Trace("letters", wi.RegexpStr("[a-zA-Z]+"))
+	p := wi.Or(digit, letters)
+
+	_, err := wi.Parse("!", p)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	pe, ok := err.(*wi.ParseError)
+	if !ok {
+		t.Fatalf("expected *wi.ParseError, got %T", err)
+	}
+	if pe.Pos != (wi.Pos{Line: 1, Col: 1}) {
+		t.Errorf("Pos = %s, want 1:1", pe.Pos)
+	}
+	if pe.Got != '!' {
+		t.Errorf("Got = %q, want '!'", pe.Got)
+	}
+	if len(pe.Expected) != 2 || pe.Expected[0] != "digit" || pe.Expected[1] != "letters" {
+		t.Errorf("Expected = %v, want [digit letters]", pe.Expected)
+	}
+}
+
+func TestFarthestFailureDiscardsCloserFailures(t *testing.T) {
+	// the first branch fails immediately; the second consumes "a"
+	// before failing on "b", so only the second branch's expectation
+	// should survive.
+	p := wi.Or(
+		wi.Trace("upper1", wi.RegexpStr("[A-Z]")),
+		wi.Map(
+			wi.And(wi.Trace("lower", wi.RegexpStr("[a-z]")), wi.Trace("upper2", wi.RegexpStr("[A-Z]"))),
+			func(t wi.Tuple2[string, string]) string { return t.V0 + t.V1 },
+		),
+	)
+
+	_, err := wi.Parse("ab", p)
+	pe, ok := err.(*wi.ParseError)
+	if !ok {
+		t.Fatalf("expected *wi.ParseError, got %T: %v", err, err)
+	}
+	if pe.Pos != (wi.Pos{Line: 1, Col: 2}) {
+		t.Errorf("Pos = %s, want 1:2", pe.Pos)
+	}
+	if len(pe.Expected) != 1 || pe.Expected[0] != "upper2" {
+		t.Errorf("Expected = %v, want [upper2]", pe.Expected)
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	_, err := wi.Parse("!", wi.Trace("digit", wi.RegexpStr("[0-9]+")))
+	want := regexp.MustCompile(`^at 1:1: expected one of \{digit\}, got '!'$`)
+	if !want.MatchString(err.Error()) {
+		t.Errorf("message %q does not match %s", err.Error(), want)
+	}
+}
@@ -0,0 +1,140 @@
+package waffleiron
+
+import "github.com/pkg/errors"
+
+// Memo wraps p so that repeated calls at the same input position
+// return a cached (result, error) pair instead of re-running p. This
+// turns grammars built from deeply nested Or/And chains from
+// exponential into linear time (packrat parsing).
+//
+// Combined with the seed-growing loop below, Memo also lets a rule
+// call itself at the position it started at, i.e. direct left
+// recursion such as `expr = expr "+" term | term`, without looping
+// forever. name identifies the rule for memoization and must be
+// distinct per rule; it is usually the grammar rule's name.
+func Memo[T any](name string, p Parser[T]) Parser[T] {
+	return memoParser[T]{name, p}
+}
+
+type memoParser[T any] struct {
+	name string
+	p    Parser[T]
+}
+
+type memoKey struct {
+	name string
+	idx  int64
+}
+
+type memoResult struct {
+	value  interface{}
+	err    error
+	endIdx int64
+	endPos Pos
+}
+
+// head records, per input position, which rules re-entered themselves
+// there during their first pass, i.e. are directly left-recursive at
+// that position; Parse runs grow only for those. The cache lookup at
+// the top of Parse does not consult heads, and cached results of other
+// rules called during a growth loop are reused as they are rather than
+// recomputed.
+type head struct {
+	involved map[string]bool
+}
+
+// describe treats p.name as a production rule, just like Trace: the
+// first time it's reached its body is walked and recorded, and any
+// further reference -- including the recursive self-reference that
+// drives left-recursion growth -- resolves to a reference. See
+// describeNamed.
+func (p memoParser[T]) describe(w *describeWalker) Expr {
+	return describeNamed(w, p.name, p.p)
+}
+
+// Parse implements Parser interface
+func (p memoParser[T]) Parse(r *Reader) (T, error) {
+	key := memoKey{p.name, r.idx}
+
+	if m, ok := r.memo[key]; ok {
+		r.idx, r.pos = m.endIdx, m.endPos
+		return castMemo[T](m)
+	}
+
+	for _, k := range r.involved {
+		if k == key {
+			// Re-entering this exact rule at the same position: this is
+			// direct left recursion. Seed the cache with a failure so the
+			// recursive call bottoms out, and record that p.name is
+			// growing a seed at this position.
+			seed := memoResult{
+				err:    errors.Errorf("left recursion seed for %q at %s", p.name, r.pos),
+				endIdx: r.idx,
+				endPos: r.pos,
+			}
+			r.memo[key] = seed
+			h := r.heads[r.idx]
+			if h == nil {
+				h = &head{involved: map[string]bool{}}
+				r.heads[r.idx] = h
+			}
+			h.involved[p.name] = true
+			return *new(T), seed.err
+		}
+	}
+
+	// startIdx is held as a savepoint for the rest of this call, the
+	// same way Try holds one for its own start: both the initial pass
+	// below and, if it turns out to be left-recursive, grow's repeated
+	// rewinding need startIdx's bytes to still be buffered in stream
+	// mode, and neither is itself wrapped in a Try that would pin them.
+	r.involved = append(r.involved, key)
+	startIdx, startPos := r.idx, r.pos
+	r.savepoints = append(r.savepoints, startIdx)
+	v, err := p.p.Parse(r)
+	r.involved = r.involved[:len(r.involved)-1]
+
+	best := memoResult{value: v, err: err, endIdx: r.idx, endPos: r.pos}
+	r.memo[key] = best
+
+	if h := r.heads[startIdx]; h != nil && h.involved[p.name] && err == nil {
+		best = p.grow(r, startIdx, startPos, best)
+		delete(r.heads, startIdx)
+		r.memo[key] = best
+	}
+
+	r.savepoints = r.savepoints[:len(r.savepoints)-1]
+	r.reclaim()
+
+	return castMemo[T](best)
+}
+
+// grow re-runs p.p from startIdx, with the memo for (p.name, startIdx)
+// seeded with the current best result, until a re-run fails or stops
+// consuming more input than the previous best. This is the "grow" half
+// of Warth's seed-growing algorithm: each iteration, the recursive call
+// to p.name at startIdx returns the previous iteration's result instead
+// of recursing again, so the rule body effectively parses "one more
+// repetition" of the left-recursive alternative per iteration. Parse
+// holds a savepoint at startIdx for the duration of this call, so in
+// stream mode the repeated rewind below always lands on buffered bytes.
+func (p memoParser[T]) grow(r *Reader, startIdx int64, startPos Pos, best memoResult) memoResult {
+	key := memoKey{p.name, startIdx}
+	for {
+		r.idx, r.pos = startIdx, startPos
+		r.memo[key] = best
+		v, err := p.p.Parse(r)
+		if err != nil || r.idx <= best.endIdx {
+			r.idx, r.pos = best.endIdx, best.endPos
+			return best
+		}
+		best = memoResult{value: v, err: nil, endIdx: r.idx, endPos: r.pos}
+	}
+}
+
+func castMemo[T any](m memoResult) (T, error) {
+	if m.err != nil {
+		return *new(T), m.err
+	}
+	return m.value.(T), nil
+}
@@ -0,0 +1,71 @@
+package waffleiron_test
+
+import (
+	"strconv"
+	"testing"
+
+	wi "github.com/seiyab/waffleiron"
+)
+
+type exprNode struct {
+	op          byte
+	left, right *exprNode
+	val         int
+}
+
+func (n *exprNode) String() string {
+	if n.op == 0 {
+		return strconv.Itoa(n.val)
+	}
+	return "(" + n.left.String() + string(n.op) + n.right.String() + ")"
+}
+
+// exprParser builds `expr = expr "+" term | term`, a directly
+// left-recursive rule, using Memo to make it terminate.
+func exprParser() wi.Parser[*exprNode] {
+	var expr wi.Parser[*exprNode]
+	term := wi.Map(wi.Int(), func(v int) *exprNode { return &exprNode{val: v} })
+	expr = wi.Memo("expr", wi.FuncParser[*exprNode](func(r *wi.Reader) (*exprNode, error) {
+		return wi.Or(
+			wi.Map(
+				wi.And3(expr, wi.Rune('+'), term),
+				func(t wi.Tuple3[*exprNode, rune, *exprNode]) *exprNode {
+					return &exprNode{op: '+', left: t.V0, right: t.V2}
+				},
+			),
+			term,
+		).Parse(r)
+	}))
+	return expr
+}
+
+func TestMemoLeftRecursion(t *testing.T) {
+	got, err := wi.Parse("1+2+3+4", exprParser())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(((1+2)+3)+4)"; got.String() != want {
+		t.Errorf("got %s, want %s", got.String(), want)
+	}
+}
+
+func TestMemoAvoidsExponentialBacktracking(t *testing.T) {
+	// an Or where every branch but the last fails should still only
+	// call the memoized sub-parser once per position.
+	calls := 0
+	counted := wi.Memo("counted", wi.FuncParser[string](func(r *wi.Reader) (string, error) {
+		calls++
+		return wi.String("x").Parse(r)
+	}))
+	p := wi.Or(
+		wi.And(counted, wi.String("a")),
+		wi.And(counted, wi.String("b")),
+		wi.And(counted, wi.String("x")),
+	)
+	if _, err := wi.Parse("xx", p); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("counted was called %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,48 @@
+package waffleiron
+
+// Parser is implemented by every combinator in this package. Parse
+// consumes zero or more bytes from r and returns a value of type T, or
+// an error if the input does not match.
+type Parser[T any] interface {
+	Parse(r *Reader) (T, error)
+}
+
+// Parse runs p against the whole of str and returns its result. It is
+// an error for p to succeed without consuming all of str.
+func Parse[T any](str string, p Parser[T]) (T, error) {
+	r := newReader(str)
+	v, err := p.Parse(r)
+	if err != nil {
+		return v, err
+	}
+	if r.remainingString() != "" {
+		r.recordFailure(r.idx, r.pos, "EOF")
+		return *new(T), r.farthestError()
+	}
+	return v, nil
+}
+
+// Map returns a Parser that transforms the result of p with f.
+func Map[T, U any](p Parser[T], f func(T) U) Parser[U] {
+	return mapParser[T, U]{p, f}
+}
+
+type mapParser[T, U any] struct {
+	p Parser[T]
+	f func(T) U
+}
+
+// describe inlines p.p: Map only transforms the result value, so it
+// has no effect on the grammar's shape.
+func (p mapParser[T, U]) describe(w *describeWalker) Expr {
+	return w.walk(p.p)
+}
+
+// Parse implements Parser interface
+func (p mapParser[T, U]) Parse(r *Reader) (U, error) {
+	t, err := p.p.Parse(r)
+	if err != nil {
+		return *new(U), err
+	}
+	return p.f(t), nil
+}
@@ -5,8 +5,6 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-
-	"github.com/pkg/errors"
 )
 
 type FuncParser[T any] func(r *reader) (T, error)
@@ -24,14 +22,17 @@ type runeParser struct {
 	rn rune
 }
 
+func (p runeParser) describe(w *describeWalker) Expr {
+	return Expr{Kind: KindLiteral, Literal: string(p.rn)}
+}
+
 // Parse implements Parser interface
 func (p runeParser) Parse(r *reader) (rune, error) {
+	startIdx, startPos := r.idx, r.pos
 	ch, _, err := r.readRune()
-	if err != nil {
-		return 0, errors.Wrapf(err, "at %s", r.pos)
-	}
-	if ch != p.rn {
-		return 0, errors.Errorf("expected %q, found %q at %s", p.rn, ch, r.pos)
+	if err != nil || ch != p.rn {
+		r.recordFailure(startIdx, startPos, fmt.Sprintf("%q", p.rn))
+		return 0, r.farthestError()
 	}
 	return ch, nil
 }
@@ -45,11 +46,15 @@ type stringParser struct {
 	str string
 }
 
+func (p stringParser) describe(w *describeWalker) Expr {
+	return Expr{Kind: KindLiteral, Literal: p.str}
+}
+
 // Parse implements Parser interface
 func (p stringParser) Parse(r *reader) (string, error) {
-	overrun := int64(len(p.str)) > int64(len(r.str))-r.idx
-	if overrun || !strings.HasPrefix(r.remainingString(), p.str) {
-		return "", errors.Errorf("expected %q, but not found at %s", p.str, r.pos)
+	if !strings.HasPrefix(r.remainingString(), p.str) {
+		r.recordFailure(r.idx, r.pos, fmt.Sprintf("%q", p.str))
+		return "", r.farthestError()
 	}
 	s, err := r.consumeBytes(len(p.str))
 	if err != nil || s != p.str {
@@ -61,7 +66,9 @@ func (p stringParser) Parse(r *reader) (string, error) {
 	return p.str, nil
 }
 
-// Regexp returns Parser that consume a string and return it if remaining string matches re
+// Regexp returns Parser that consume a string and return it if remaining string matches re.
+// Under ParseStream, re is matched against at most streamLookahead bytes of lookahead, so it
+// cannot be used to match tokens longer than that; use Many1 with a rune-level parser instead.
 func Regexp(re *regexp.Regexp) Parser[string] {
 	if !strings.HasPrefix(re.String(), "^") {
 		return regexpParser{
@@ -75,12 +82,17 @@ type regexpParser struct {
 	re *regexp.Regexp
 }
 
+func (p regexpParser) describe(w *describeWalker) Expr {
+	return Expr{Kind: KindPattern, Pattern: p.re.String()}
+}
+
 // Parse implements Parser interface
 func (p regexpParser) Parse(r *reader) (string, error) {
 	str := r.remainingString()
 	loc := p.re.FindStringIndex(str)
 	if len(loc) == 0 {
-		return "", errors.Errorf("expected to match %q at %s", p.re, r.pos)
+		r.recordFailure(r.idx, r.pos, p.re.String())
+		return "", r.farthestError()
 	}
 	if loc[0] != 0 {
 		panic("regex matched on loc[0] != 0. it might be bug. please submit an issue.")
@@ -129,6 +141,10 @@ type pureParser[T any] struct {
 	value T
 }
 
+func (p pureParser[T]) describe(w *describeWalker) Expr {
+	return Expr{Kind: KindEmpty}
+}
+
 // Parse implements Parser interface
 func (p pureParser[T]) Parse(r *reader) (T, error) {
 	return p.value, nil
@@ -0,0 +1,161 @@
+package waffleiron
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Reader is the exported name for reader, so doc comments and type
+// signatures in this package can refer to it without letting callers
+// outside the package construct one directly; Parse and ParseStream do
+// that.
+type Reader = reader
+
+// Pos is a 1-indexed line and column within the input, used in error
+// messages.
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Col)
+}
+
+type reader struct {
+	str string
+	idx int64
+	pos Pos
+
+	trace []string
+
+	memo     map[memoKey]memoResult
+	involved []memoKey
+	heads    map[int64]*head
+
+	hasFarthest      bool
+	farthestIdx      int64
+	farthestPos      Pos
+	farthestExpected map[string]bool
+
+	// stream mode: when src is non-nil, str is unused and bytes are
+	// buffered on demand into buf; see stream.go.
+	src        io.Reader
+	buf        []byte
+	base       int64
+	eof        bool
+	committed  int64
+	savepoints []int64
+}
+
+func newReader(str string) *reader {
+	return &reader{
+		str:   str,
+		pos:   Pos{Line: 1, Col: 1},
+		memo:  map[memoKey]memoResult{},
+		heads: map[int64]*head{},
+	}
+}
+
+// remainingString returns the part of the input that has not been
+// consumed yet. In stream mode this is bounded to at most
+// streamLookahead bytes past idx; see stream.go.
+func (r *reader) remainingString() string {
+	if r.src != nil {
+		r.fill(streamLookahead)
+		end := r.idx - r.base + int64(streamLookahead)
+		if bufEnd := int64(len(r.buf)); end > bufEnd {
+			end = bufEnd
+		}
+		return string(r.buf[r.idx-r.base : end])
+	}
+	return r.str[r.idx:]
+}
+
+// readRune consumes and returns the next rune.
+func (r *reader) readRune() (rune, int, error) {
+	if r.src != nil {
+		r.fill(utf8.UTFMax)
+	}
+	if !r.more() {
+		return 0, 0, errors.Errorf("unexpected end of input at %s", r.pos)
+	}
+	ch, size := utf8.DecodeRuneInString(r.remainingString())
+	if _, err := r.consumeBytes(size); err != nil {
+		panic("waffleiron: readRune failed to consume a rune it just decoded")
+	}
+	return ch, size, nil
+}
+
+// consumeBytes consumes and returns the next n bytes.
+func (r *reader) consumeBytes(n int) (string, error) {
+	if r.src != nil {
+		r.fill(n)
+		if int64(n) > r.base+int64(len(r.buf))-r.idx {
+			return "", errors.Errorf("unexpected end of input at %s", r.pos)
+		}
+		out := string(r.buf[r.idx-r.base : r.idx-r.base+int64(n)])
+		r.advance(out)
+		return out, nil
+	}
+	if int64(n) > int64(len(r.str))-r.idx {
+		return "", errors.Errorf("unexpected end of input at %s", r.pos)
+	}
+	out := r.str[r.idx : r.idx+int64(n)]
+	r.advance(out)
+	return out, nil
+}
+
+// more reports whether at least one more byte is available, filling
+// from src in stream mode if needed.
+func (r *reader) more() bool {
+	if r.src != nil {
+		r.fill(1)
+		return r.idx < r.base+int64(len(r.buf))
+	}
+	return r.idx < int64(len(r.str))
+}
+
+// advance moves idx and pos past consumed, which must be the bytes
+// starting at the current idx.
+func (r *reader) advance(consumed string) {
+	r.idx += int64(len(consumed))
+	for _, ch := range consumed {
+		if ch == '\n' {
+			r.pos.Line++
+			r.pos.Col = 1
+		} else {
+			r.pos.Col++
+		}
+	}
+}
+
+// Try runs f, rewinding the reader to the position it had before f ran
+// if f returns an error. While f runs, idx is a savepoint: in stream
+// mode, ParseStream keeps buffering bytes from idx onward so Try can
+// still rewind there even if f advances far past it.
+func (r *reader) Try(f func() error) error {
+	idx, pos := r.idx, r.pos
+	r.savepoints = append(r.savepoints, idx)
+	err := f()
+	r.savepoints = r.savepoints[:len(r.savepoints)-1]
+	if err != nil {
+		r.idx, r.pos = idx, pos
+	}
+	// the savepoint at idx is gone now, whether f succeeded or failed
+	// back to it; bytes before the oldest savepoint still open (or idx
+	// itself, if none is) can be released.
+	r.reclaim()
+	return err
+}
+
+// WithTrace runs f with name pushed onto the reader's trace stack and
+// pops it when f returns. Nothing in this package reads the stack.
+func (r *reader) WithTrace(name string, f func()) {
+	r.trace = append(r.trace, name)
+	f()
+	r.trace = r.trace[:len(r.trace)-1]
+}
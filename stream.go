@@ -0,0 +1,91 @@
+package waffleiron
+
+import "io"
+
+// streamLookahead bounds how many bytes of input a parser can look at
+// in one go in stream mode (via remainingString, used by Regexp and
+// String). A grammar that needs a Regexp or String to match a longer
+// run than this will fail even on otherwise-valid input; grammars with
+// long tokens should split them into Many1(RuneWhere(...)) or similar.
+// The bound is fixed; it does not depend on the io.Reader passed to
+// ParseStream.
+const streamLookahead = 256
+
+const streamChunk = 32 * 1024
+
+// ParseStream runs p against src without holding the whole input in
+// memory: bytes are buffered on demand into a bounded window, and
+// bytes before the oldest open savepoint (see Try) are released as
+// parsing advances, so memory use stays bounded regardless of input
+// size. As with Parse, it is an error for p to succeed without
+// consuming all of src.
+func ParseStream[T any](src io.Reader, p Parser[T]) (T, error) {
+	r := newStreamReader(src)
+	v, err := p.Parse(r)
+	if err != nil {
+		return v, err
+	}
+	if r.more() {
+		r.recordFailure(r.idx, r.pos, "EOF")
+		return *new(T), r.farthestError()
+	}
+	return v, nil
+}
+
+func newStreamReader(src io.Reader) *reader {
+	return &reader{
+		pos:   Pos{Line: 1, Col: 1},
+		memo:  map[memoKey]memoResult{},
+		heads: map[int64]*head{},
+		src:   src,
+	}
+}
+
+// fill ensures at least n bytes are buffered from idx onward, or that
+// src is exhausted.
+func (r *reader) fill(n int) {
+	need := r.idx - r.base + int64(n)
+	for !r.eof && int64(len(r.buf)) < need {
+		chunk := make([]byte, streamChunk)
+		k, err := r.src.Read(chunk)
+		if k > 0 {
+			r.buf = append(r.buf, chunk[:k]...)
+		}
+		if err != nil {
+			r.eof = true
+		}
+	}
+}
+
+// Commit tells the reader that bytes before the current position will
+// never be needed again, even if an enclosing Try/Or would otherwise
+// keep them buffered to support backtracking there. It has no effect
+// in non-streaming mode.
+func (r *reader) Commit() {
+	if r.idx > r.committed {
+		r.committed = r.idx
+	}
+	r.reclaim()
+}
+
+// reclaim drops buffered bytes before the low-water mark: the oldest
+// open savepoint, or idx if there is none, whichever is earlier,
+// pulled forward by any explicit Commit.
+func (r *reader) reclaim() {
+	if r.src == nil {
+		return
+	}
+	low := r.idx
+	for _, sp := range r.savepoints {
+		if sp < low {
+			low = sp
+		}
+	}
+	if r.committed > low {
+		low = r.committed
+	}
+	if low > r.base {
+		r.buf = r.buf[low-r.base:]
+		r.base = low
+	}
+}
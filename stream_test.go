@@ -0,0 +1,159 @@
+package waffleiron_test
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	wi "github.com/seiyab/waffleiron"
+)
+
+// repeatReader streams n copies of s without ever materializing the
+// whole thing at once.
+type repeatReader struct {
+	s  string
+	n  int
+	i  int
+	at int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	total := 0
+	for total < len(p) {
+		if r.i >= r.n {
+			if total > 0 {
+				return total, nil
+			}
+			return 0, io.EOF
+		}
+		k := copy(p[total:], r.s[r.at:])
+		total += k
+		r.at += k
+		if r.at == len(r.s) {
+			r.at = 0
+			r.i++
+		}
+	}
+	return total, nil
+}
+
+// countLines repeatedly tries line until it stops matching, calling
+// Commit after each success so ParseStream can release input behind it
+// as it goes, and returns how many times it matched.
+func countLines(line wi.Parser[string]) wi.Parser[int] {
+	return wi.FuncParser[int](func(r *wi.Reader) (int, error) {
+		count := 0
+		for {
+			err := r.Try(func() error {
+				_, e := line.Parse(r)
+				return e
+			})
+			if err != nil {
+				return count, nil
+			}
+			count++
+			r.Commit()
+		}
+	})
+}
+
+func TestParseStreamLargeInput(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping 100MB stream parse in -short mode")
+	}
+
+	const line = "hello, waffleiron\n"
+	const n = 100 * 1024 * 1024 / len(line)
+
+	word := wi.RegexpStr(`[a-z]+`)
+	lineParser := wi.Map(
+		wi.And3(word, wi.String(", "), wi.And(word, wi.Rune('\n'))),
+		func(t wi.Tuple3[string, string, wi.Tuple2[string, rune]]) string { return t.V0 },
+	)
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	got, err := wi.ParseStream(&repeatReader{s: line, n: n}, countLines(lineParser))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != n {
+		t.Fatalf("got %d lines, want %d", got, n)
+	}
+
+	runtime.GC()
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	if grew := int64(after.HeapAlloc) - int64(before.HeapAlloc); grew > 512*1024 {
+		t.Errorf("heap grew by %d bytes parsing a %dMB stream, want it bounded to a few KB", grew, len(line)*n/1024/1024)
+	}
+}
+
+func TestParseStreamFarthestFailureBeforeEOF(t *testing.T) {
+	_, err := wi.ParseStream(strings.NewReader("hxllo world"), wi.String("hello"))
+	pe, ok := err.(*wi.ParseError)
+	if !ok {
+		t.Fatalf("expected *wi.ParseError, got %T: %v", err, err)
+	}
+	if pe.AtEOF {
+		t.Errorf("AtEOF = true, want false: failure was at the start of the input, not its end")
+	}
+	if pe.Got != 'h' {
+		t.Errorf("Got = %q, want 'h'", pe.Got)
+	}
+}
+
+// TestParseStreamLeftRecursiveMemo commits a large amount of filler
+// before reaching a Memo-backed left-recursive rule further into the
+// same stream. Without a savepoint pinning the rule's start position
+// for the duration of its seed-growing loop, the Or branches tried
+// while growing the seed would reclaim buffered bytes out from under
+// the next rewind and panic with a negative slice index.
+func TestParseStreamLeftRecursiveMemo(t *testing.T) {
+	const filler = "noise\n"
+	const fillerLines = 10000 // several times the internal stream chunk size
+
+	term := wi.Map(wi.RegexpStr(`[0-9]+`), func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	})
+	var expr wi.Parser[int]
+	expr = wi.Memo("expr", wi.Or(
+		wi.Map(
+			wi.And3(wi.Lazy(func() wi.Parser[int] { return expr }), wi.Rune('+'), term),
+			func(t wi.Tuple3[int, rune, int]) int { return t.V0 + t.V2 },
+		),
+		term,
+	))
+
+	p := wi.FuncParser[int](func(r *wi.Reader) (int, error) {
+		for i := 0; i < fillerLines; i++ {
+			if _, err := wi.String(filler).Parse(r); err != nil {
+				return 0, err
+			}
+			r.Commit()
+		}
+		return expr.Parse(r)
+	})
+
+	input := strings.Repeat(filler, fillerLines) + "1+2+3+4"
+	got, err := wi.ParseStream(strings.NewReader(input), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 10 {
+		t.Errorf("got %d, want 10", got)
+	}
+}
+
+func ExampleParseStream() {
+	p := wi.And(wi.RegexpStr(`[a-z]+`), wi.Rune('!'))
+	v, _ := wi.ParseStream(strings.NewReader("hello!"), p)
+	fmt.Println(v.V0, string(v.V1))
+	// Output: hello !
+}
@@ -0,0 +1,26 @@
+package waffleiron
+
+// Tuple2 holds the results of two parsers run in sequence, as returned
+// by And.
+type Tuple2[T, U any] struct {
+	V0 T
+	V1 U
+}
+
+// NewTuple2 builds a Tuple2 from its two values.
+func NewTuple2[T, U any](v0 T, v1 U) Tuple2[T, U] {
+	return Tuple2[T, U]{v0, v1}
+}
+
+// Tuple3 holds the results of three parsers run in sequence, as
+// returned by And3.
+type Tuple3[T, U, V any] struct {
+	V0 T
+	V1 U
+	V2 V
+}
+
+// NewTuple3 builds a Tuple3 from its three values.
+func NewTuple3[T, U, V any](v0 T, v1 U, v2 V) Tuple3[T, U, V] {
+	return Tuple3[T, U, V]{v0, v1, v2}
+}
@@ -0,0 +1,102 @@
+package waffleiron
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// RuneWhere returns a Parser that consumes the next rune if pred
+// reports true for it.
+func RuneWhere(pred func(rune) bool) Parser[rune] {
+	return runeWhereParser{pred: pred, label: "rune matching predicate"}
+}
+
+// Satisfy is an alias for RuneWhere, for callers used to the classic
+// parser-combinator name for a single-token predicate parser.
+func Satisfy(pred func(rune) bool) Parser[rune] {
+	return RuneWhere(pred)
+}
+
+type runeWhereParser struct {
+	pred  func(rune) bool
+	label string
+}
+
+func (p runeWhereParser) describe(w *describeWalker) Expr {
+	return Expr{Kind: KindPattern, Pattern: p.label}
+}
+
+// Parse implements Parser interface
+func (p runeWhereParser) Parse(r *Reader) (rune, error) {
+	startIdx, startPos := r.idx, r.pos
+	ch, _, err := r.readRune()
+	if err != nil || !p.pred(ch) {
+		r.idx, r.pos = startIdx, startPos
+		r.recordFailure(startIdx, startPos, p.label)
+		return 0, r.farthestError()
+	}
+	return ch, nil
+}
+
+// RuneIn returns a Parser that consumes the next rune if it belongs to
+// table, e.g. RuneIn(unicode.Han) or RuneIn(unicode.Letter).
+func RuneIn(table *unicode.RangeTable) Parser[rune] {
+	return runeWhereParser{
+		pred:  func(ch rune) bool { return unicode.Is(table, ch) },
+		label: "rune in table",
+	}
+}
+
+// RuneRange returns a Parser that consumes the next rune if it falls
+// between lo and hi, inclusive.
+func RuneRange(lo, hi rune) Parser[rune] {
+	return runeWhereParser{
+		pred:  func(ch rune) bool { return lo <= ch && ch <= hi },
+		label: fmt.Sprintf("rune in %q-%q", lo, hi),
+	}
+}
+
+// StringFold returns a Parser that matches s using Unicode simple case
+// folding rather than an exact byte match, and returns the substring
+// actually consumed, which may differ from s in case.
+func StringFold(s string) Parser[string] {
+	return stringFoldParser{s}
+}
+
+type stringFoldParser struct {
+	str string
+}
+
+func (p stringFoldParser) describe(w *describeWalker) Expr {
+	return Expr{Kind: KindLiteral, Literal: p.str}
+}
+
+// Parse implements Parser interface
+func (p stringFoldParser) Parse(r *Reader) (string, error) {
+	startIdx, startPos := r.idx, r.pos
+	var consumed []rune
+	for _, want := range p.str {
+		ch, _, err := r.readRune()
+		if err != nil || !foldEquals(ch, want) {
+			r.idx, r.pos = startIdx, startPos
+			r.recordFailure(startIdx, startPos, fmt.Sprintf("%q (case-insensitive)", p.str))
+			return "", r.farthestError()
+		}
+		consumed = append(consumed, ch)
+	}
+	return string(consumed), nil
+}
+
+// foldEquals reports whether a and b are the same rune under Unicode
+// simple case folding.
+func foldEquals(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
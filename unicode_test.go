@@ -0,0 +1,70 @@
+package waffleiron_test
+
+import (
+	"testing"
+	"unicode"
+
+	wi "github.com/seiyab/waffleiron"
+)
+
+func TestRuneIn(t *testing.T) {
+	p := wi.RuneIn(unicode.Han)
+
+	if _, err := wi.Parse("波", p); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if _, err := wi.Parse("a", p); err == nil {
+		t.Error("expected error for a non-Han rune")
+	}
+}
+
+func TestRuneRange(t *testing.T) {
+	p := wi.RuneRange('a', 'f')
+
+	for _, tt := range []string{"a", "c", "f"} {
+		if _, err := wi.Parse(tt, p); err != nil {
+			t.Errorf("%q: unexpected error: %s", tt, err)
+		}
+	}
+	for _, tt := range []string{"g", "A", "0"} {
+		if _, err := wi.Parse(tt, p); err == nil {
+			t.Errorf("%q: expected error", tt)
+		}
+	}
+}
+
+func TestIdentWithSatisfyAndMany1(t *testing.T) {
+	ident := wi.Map(wi.Many1(wi.Satisfy(unicode.IsLetter)), func(rs []rune) string { return string(rs) })
+
+	got, err := wi.Parse("WaffleIron", ident)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "WaffleIron" {
+		t.Errorf("got %q, want %q", got, "WaffleIron")
+	}
+
+	if _, err := wi.Parse("", ident); err == nil {
+		t.Error("expected error for empty input")
+	}
+}
+
+func TestStringFold(t *testing.T) {
+	p := wi.StringFold("SELECT")
+
+	for _, tt := range []string{"SELECT", "select", "Select", "sElEcT"} {
+		t.Run(tt, func(t *testing.T) {
+			got, err := wi.Parse(tt, p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt {
+				t.Errorf("got %q, want %q", got, tt)
+			}
+		})
+	}
+
+	if _, err := wi.Parse("SELECTED", p); err == nil {
+		t.Error("expected error for trailing input")
+	}
+}
@@ -0,0 +1,72 @@
+// Package waffleirontest helps test waffleiron grammars against
+// fixtures in the style of go/parser's own testdata: a source string
+// with an inline `/* ERROR "regexp" */` comment marking exactly where
+// parsing is expected to fail and what the error should say.
+package waffleirontest
+
+import (
+	"regexp"
+	"testing"
+
+	wi "github.com/seiyab/waffleiron"
+)
+
+var marker = regexp.MustCompile(`/\* ?ERROR "([^"]*)" ?\*/`)
+
+// Case parses src, a grammar fixture containing exactly one
+// `/* ERROR "regexp" */` marker, with p. The marker is stripped before
+// parsing and Case asserts that p fails at precisely the byte offset
+// the marker occupied, with an error message matching regexp.
+func Case[T any](t *testing.T, src string, p wi.Parser[T]) {
+	t.Helper()
+
+	loc := marker.FindStringSubmatchIndex(src)
+	if loc == nil {
+		t.Fatalf(`test fixture has no /* ERROR "regexp" */ marker`)
+	}
+	if marker.FindStringIndex(src[loc[1]:]) != nil {
+		t.Fatalf("test fixture has more than one ERROR marker; Case supports exactly one")
+	}
+
+	wantPattern := src[loc[2]:loc[3]]
+	wantOffset := loc[0]
+	clean := src[:loc[0]] + src[loc[1]:]
+
+	want, err := regexp.Compile(wantPattern)
+	if err != nil {
+		t.Fatalf("invalid ERROR pattern %q: %s", wantPattern, err)
+	}
+
+	_, err = wi.Parse(clean, p)
+	if err == nil {
+		t.Fatalf("expected parse error matching %q, got none", wantPattern)
+	}
+	pe, ok := err.(*wi.ParseError)
+	if !ok {
+		t.Fatalf("expected a *waffleiron.ParseError, got %T: %s", err, err)
+	}
+	if got := byteOffset(clean, pe.Pos); got != wantOffset {
+		t.Errorf("parse failed at byte %d, want %d (error: %s)", got, wantOffset, pe)
+	}
+	if !want.MatchString(pe.Error()) {
+		t.Errorf("error message %q does not match %q", pe.Error(), wantPattern)
+	}
+}
+
+// byteOffset converts a 1-indexed line:col position back into a byte
+// offset into src.
+func byteOffset(src string, pos wi.Pos) int {
+	line, col := 1, 1
+	for i, ch := range src {
+		if line == pos.Line && col == pos.Col {
+			return i
+		}
+		if ch == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	return len(src)
+}
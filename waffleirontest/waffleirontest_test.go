@@ -0,0 +1,14 @@
+package waffleirontest_test
+
+import (
+	"testing"
+
+	wi "github.com/seiyab/waffleiron"
+	"github.com/seiyab/waffleiron/waffleirontest"
+)
+
+func TestCase(t *testing.T) {
+	digits := wi.Trace("digits", wi.RegexpStr("[0-9]+"))
+
+	waffleirontest.Case(t, `/* ERROR "digits" */x12`, digits)
+}